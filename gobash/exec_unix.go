@@ -0,0 +1,18 @@
+//go:build !windows
+package main
+import (
+	"os"
+	"syscall"
+)
+// execShell replaces the current process image with the program at path,
+// run with argv, so the shell
+// inherits PID/PGID/session directly instead of running as a child of this
+// launcher. If stdin is non-nil it is dup'd onto fd 0 before the exec.
+func execShell(path string, argv []string, env []string, stdin *os.File) error {
+	if stdin != nil {
+		if err := syscall.Dup2(int(stdin.Fd()), 0); err != nil {
+			return err
+		}
+	}
+	return syscall.Exec(path, argv, env)
+}
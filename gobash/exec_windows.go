@@ -0,0 +1,25 @@
+//go:build windows
+package main
+import (
+	"os"
+	"os/exec"
+)
+// execShell falls back to cmd.Run on platforms without syscall.Exec. Args is
+// set explicitly to argv (rather than via exec.Command's variadic args)
+// because argv[0] must stay "ash", not the resolved busybox path, for
+// busybox's multi-call dispatch to pick the right applet.
+func execShell(path string, argv []string, env []string, stdin *os.File) error {
+	in := os.Stdin
+	if stdin != nil {
+		in = stdin
+	}
+	cmd := &exec.Cmd{
+		Path:   path,
+		Args:   argv,
+		Env:    env,
+		Stdin:  in,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+	return cmd.Run()
+}
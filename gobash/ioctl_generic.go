@@ -0,0 +1,14 @@
+//go:build !windows && !(linux && (mips || mipsle || mips64 || mips64le || ppc64 || ppc64le))
+package main
+
+// ioctl request codes matching the asm-generic table used by amd64, 386,
+// arm, arm64, s390x, riscv64 and loong64 (and, outside Linux, the other
+// unix-family builds of this launcher).
+const (
+	ioctlTCGETS     = 0x5401
+	ioctlTCSETS     = 0x5402
+	ioctlTIOCGWINSZ = 0x5413
+	ioctlTIOCSWINSZ = 0x5414
+	ioctlTIOCGPTN   = 0x80045430
+	ioctlTIOCSPTLCK = 0x40045431
+)
@@ -0,0 +1,15 @@
+//go:build linux && (mips || mipsle || mips64 || mips64le)
+package main
+
+// ioctl request codes for the mips family, which renumbers these relative
+// to the asm-generic table every other Linux GOARCH shares (notably
+// TIOCGPTN/TIOCSPTLCK are the reverse pair of the generic values). RouterOS
+// ships these kernels on most RB7xx/hAP-lite-class boards.
+const (
+	ioctlTCGETS     = 0x540d
+	ioctlTCSETS     = 0x540e
+	ioctlTIOCGWINSZ = 0x40087468
+	ioctlTIOCSWINSZ = 0x80087467
+	ioctlTIOCGPTN   = 0x40045430
+	ioctlTIOCSPTLCK = 0x80045431
+)
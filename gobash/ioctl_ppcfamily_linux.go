@@ -0,0 +1,13 @@
+//go:build linux && (ppc64 || ppc64le)
+package main
+
+// ioctl request codes for ppc64/ppc64le, which use their own TCGETS/TCSETS
+// numbering distinct from both the asm-generic and mips tables.
+const (
+	ioctlTCGETS     = 0x402c7413
+	ioctlTCSETS     = 0x802c7414
+	ioctlTIOCGWINSZ = 0x40087468
+	ioctlTIOCSWINSZ = 0x80087467
+	ioctlTIOCGPTN   = 0x40045430
+	ioctlTIOCSPTLCK = 0x80045431
+)
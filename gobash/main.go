@@ -1,17 +1,113 @@
 package main
 import (
+	"flag"
 	"log"
 	"os"
 	"os/exec"
+	"strings"
 )
+
+// envFlags collects repeated -e/--env KEY=VAL flags.
+type envFlags []string
+
+func (e *envFlags) String() string { return strings.Join(*e, ",") }
+func (e *envFlags) Set(v string) error {
+	*e = append(*e, v)
+	return nil
+}
+
+// mergeEnv overlays overrides on top of base by KEY, last write wins. Unlike
+// a plain append, this makes a repeated -e KEY=VAL actually replace an
+// inherited KEY=VAL instead of losing to os.Environ()'s first-match lookup.
+func mergeEnv(base, overrides []string) []string {
+	values := make(map[string]string, len(base)+len(overrides))
+	var keys []string
+	set := func(kv string) {
+		key := kv
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			key = kv[:i]
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = kv
+	}
+	for _, kv := range base {
+		set(kv)
+	}
+	for _, kv := range overrides {
+		set(kv)
+	}
+	merged := make([]string, len(keys))
+	for i, key := range keys {
+		merged[i] = values[key]
+	}
+	return merged
+}
+
 func main() {
+	var (
+		script     string
+		scriptFile string
+		login      bool
+		env        envFlags
+		logPath    string
+		logFormat  string
+	)
+	flag.StringVar(&script, "c", "", "run `cmd` with ash -c")
+	flag.StringVar(&scriptFile, "s", "", "feed `scriptfile` to ash on stdin")
+	flag.BoolVar(&login, "l", false, "start ash as a login shell")
+	flag.Var(&env, "e", "KEY=VAL to add to the child environment (repeatable)")
+	flag.Var(&env, "env", "alias for -e")
+	flag.StringVar(&logPath, "log", "", "record the session transcript to `path`")
+	flag.StringVar(&logFormat, "log-format", "raw", "transcript format: raw, ttyrec, or asciicast")
+	flag.Parse()
+
 	busybox := "busybox"
+	path, err := exec.LookPath(busybox)
+	if err != nil {
+		log.Fatalf("execv error: %v", err)
+	}
+
+	rest := flag.Args()
 	args := []string{"ash"}
-	cmd := exec.Command(busybox, args...)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if login {
+		args = append(args, "-l")
+	}
+	var stdin *os.File
+	switch {
+	case script != "":
+		args = append(args, "-c", script, "ash")
+		args = append(args, rest...)
+	case scriptFile != "":
+		f, err := os.Open(scriptFile)
+		if err != nil {
+			log.Fatalf("execv error: %v", err)
+		}
+		defer f.Close()
+		stdin = f
+		args = append(args, "-s")
+		args = append(args, rest...)
+	default:
+		args = append(args, rest...)
+	}
+
+	childEnv := mergeEnv(os.Environ(), env)
+
+	if logPath != "" {
+		if err := runRecorded(path, args, childEnv, logPath, logFormat, stdin); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				os.Exit(exitErr.ExitCode())
+			}
+			log.Fatalf("execv error: %v", err)
+		}
+		return
+	}
+
+	if err := execShell(path, args, childEnv, stdin); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			os.Exit(exitErr.ExitCode())
+		}
 		log.Fatalf("execv error: %v", err)
 	}
 }
@@ -0,0 +1,87 @@
+package main
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// recorder persists a copy of a recorded session's output to the -log
+// transcript in one of the -log-format encodings.
+type recorder interface {
+	writeOutput(p []byte) error
+	resize(cols, rows uint16) error
+}
+
+// rawRecorder writes output bytes to the transcript verbatim; raw has no
+// representation for resize events.
+type rawRecorder struct {
+	w io.Writer
+}
+
+func (r *rawRecorder) writeOutput(p []byte) error {
+	_, err := r.w.Write(p)
+	return err
+}
+func (r *rawRecorder) resize(cols, rows uint16) error { return nil }
+
+// ttyrecRecorder writes the ttyrec frame format: a little-endian
+// {sec uint32, usec uint32, len uint32} header followed by len bytes of
+// data, as expected by ttyplay. Like raw, ttyrec has no resize event.
+type ttyrecRecorder struct {
+	w io.Writer
+}
+
+func (r *ttyrecRecorder) writeOutput(p []byte) error {
+	now := time.Now()
+	hdr := make([]byte, 12)
+	binary.LittleEndian.PutUint32(hdr[0:4], uint32(now.Unix()))
+	binary.LittleEndian.PutUint32(hdr[4:8], uint32(now.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(hdr[8:12], uint32(len(p)))
+	if _, err := r.w.Write(hdr); err != nil {
+		return err
+	}
+	_, err := r.w.Write(p)
+	return err
+}
+func (r *ttyrecRecorder) resize(cols, rows uint16) error { return nil }
+
+// asciicastRecorder writes the asciicast v2 format: a JSON header line
+// followed by one [elapsedSeconds, "o"|"r", data] JSON array per event.
+type asciicastRecorder struct {
+	w     io.Writer
+	start time.Time
+}
+
+func newAsciicastRecorder(w io.Writer, cols, rows uint16) (*asciicastRecorder, error) {
+	start := time.Now()
+	header := struct {
+		Version   int   `json:"version"`
+		Width     int   `json:"width"`
+		Height    int   `json:"height"`
+		Timestamp int64 `json:"timestamp"`
+	}{2, int(cols), int(rows), start.Unix()}
+	enc, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(enc, '\n')); err != nil {
+		return nil, err
+	}
+	return &asciicastRecorder{w: w, start: start}, nil
+}
+
+func (r *asciicastRecorder) event(code, data string) error {
+	enc, err := json.Marshal([]interface{}{time.Since(r.start).Seconds(), code, data})
+	if err != nil {
+		return err
+	}
+	_, err = r.w.Write(append(enc, '\n'))
+	return err
+}
+
+func (r *asciicastRecorder) writeOutput(p []byte) error { return r.event("o", string(p)) }
+func (r *asciicastRecorder) resize(cols, rows uint16) error {
+	return r.event("r", fmt.Sprintf("%dx%d", cols, rows))
+}
@@ -0,0 +1,132 @@
+package main
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+)
+
+func TestRawRecorder(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"short", "hello"},
+		{"binary", "\x00\x01\xff"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			r := &rawRecorder{w: &buf}
+			if err := r.writeOutput([]byte(c.data)); err != nil {
+				t.Fatalf("writeOutput: %v", err)
+			}
+			if buf.String() != c.data {
+				t.Fatalf("got %q, want %q", buf.String(), c.data)
+			}
+			if err := r.resize(80, 24); err != nil {
+				t.Fatalf("resize: %v", err)
+			}
+			if buf.Len() != len(c.data) {
+				t.Fatalf("resize wrote bytes to a format with no resize event")
+			}
+		})
+	}
+}
+
+func TestTtyrecRecorderFrame(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"short", "hello"},
+		{"binary", "\x00\x01\xff"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			r := &ttyrecRecorder{w: &buf}
+			if err := r.writeOutput([]byte(c.data)); err != nil {
+				t.Fatalf("writeOutput: %v", err)
+			}
+			if got, want := buf.Len(), 12+len(c.data); got != want {
+				t.Fatalf("frame length = %d, want %d", got, want)
+			}
+			hdr := buf.Bytes()[:12]
+			length := binary.LittleEndian.Uint32(hdr[8:12])
+			if int(length) != len(c.data) {
+				t.Fatalf("len field = %d, want %d", length, len(c.data))
+			}
+			if got := string(buf.Bytes()[12:]); got != c.data {
+				t.Fatalf("payload = %q, want %q", got, c.data)
+			}
+		})
+	}
+}
+
+func TestAsciicastRecorderHeader(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := newAsciicastRecorder(&buf, 132, 43); err != nil {
+		t.Fatalf("newAsciicastRecorder: %v", err)
+	}
+	line, err := bufio.NewReader(&buf).ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading header line: %v", err)
+	}
+	var header struct {
+		Version   int   `json:"version"`
+		Width     int   `json:"width"`
+		Height    int   `json:"height"`
+		Timestamp int64 `json:"timestamp"`
+	}
+	if err := json.Unmarshal([]byte(line), &header); err != nil {
+		t.Fatalf("unmarshalling header: %v", err)
+	}
+	if header.Version != 2 {
+		t.Errorf("version = %d, want 2", header.Version)
+	}
+	if header.Width != 132 || header.Height != 43 {
+		t.Errorf("size = %dx%d, want 132x43", header.Width, header.Height)
+	}
+}
+
+func TestAsciicastRecorderEvents(t *testing.T) {
+	cases := []struct {
+		name     string
+		do       func(r *asciicastRecorder) error
+		wantCode string
+		wantData string
+	}{
+		{"output", func(r *asciicastRecorder) error { return r.writeOutput([]byte("hi")) }, "o", "hi"},
+		{"resize", func(r *asciicastRecorder) error { return r.resize(80, 24) }, "r", "80x24"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			r, err := newAsciicastRecorder(&buf, 80, 24)
+			if err != nil {
+				t.Fatalf("newAsciicastRecorder: %v", err)
+			}
+			buf.Reset() // drop the header line, we only care about the event here
+			if err := c.do(r); err != nil {
+				t.Fatalf("event: %v", err)
+			}
+			var event []interface{}
+			if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+				t.Fatalf("unmarshalling event %q: %v", buf.String(), err)
+			}
+			if len(event) != 3 {
+				t.Fatalf("event has %d fields, want 3", len(event))
+			}
+			if code, _ := event[1].(string); code != c.wantCode {
+				t.Errorf("code = %q, want %q", code, c.wantCode)
+			}
+			if data, _ := event[2].(string); data != c.wantData {
+				t.Errorf("data = %q, want %q", data, c.wantData)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+//go:build !windows
+package main
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// runRecorded spawns the program at path with argv, attached to a PTY, and
+// tees its output to both
+// the user's terminal and a -log transcript, so operators get a
+// reproducible audit trail of the patched shell without external script(1).
+// If stdin is non-nil (the -s script file) it is copied to the child once
+// instead of passing the user's terminal through interactively.
+func runRecorded(path string, argv []string, env []string, logPath, logFormat string, stdin *os.File) error {
+	f, err := os.Create(logPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ptmx, tty, err := openPTY()
+	if err != nil {
+		return err
+	}
+
+	ws, err := getWinsize(os.Stdin.Fd())
+	if err != nil {
+		ws = &winsize{Rows: 24, Cols: 80}
+	}
+	setWinsize(tty.Fd(), ws)
+
+	var rec recorder
+	switch logFormat {
+	case "", "raw":
+		rec = &rawRecorder{w: f}
+	case "ttyrec":
+		rec = &ttyrecRecorder{w: f}
+	case "asciicast":
+		rec, err = newAsciicastRecorder(f, ws.Cols, ws.Rows)
+		if err != nil {
+			tty.Close()
+			ptmx.Close()
+			return err
+		}
+	default:
+		tty.Close()
+		ptmx.Close()
+		return fmt.Errorf("unknown -log-format %q", logFormat)
+	}
+
+	// Args is set explicitly to argv (rather than via exec.Command's
+	// variadic args) because argv[0] must stay "ash", not the resolved
+	// busybox path, for busybox's multi-call dispatch to pick the applet.
+	cmd := &exec.Cmd{
+		Path:        path,
+		Args:        argv,
+		Env:         env,
+		Stdin:       tty,
+		Stdout:      tty,
+		Stderr:      tty,
+		SysProcAttr: &syscall.SysProcAttr{Setsid: true, Setctty: true},
+	}
+	if err := cmd.Start(); err != nil {
+		tty.Close()
+		ptmx.Close()
+		return err
+	}
+	tty.Close()
+
+	if stdin != nil {
+		// A PTY slave in canonical mode has no notion of EOF from a writer
+		// just stopping, so once the script is exhausted we still need to
+		// signal end-of-input with the line discipline's EOT character or
+		// ash blocks forever waiting for more.
+		go func() {
+			io.Copy(ptmx, stdin)
+			ptmx.Write([]byte{0x04})
+		}()
+	} else {
+		if old, err := makeRaw(os.Stdin.Fd()); err == nil {
+			defer restoreTermios(os.Stdin.Fd(), old)
+		}
+		go io.Copy(ptmx, os.Stdin)
+	}
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+	go func() {
+		for range winch {
+			if ws, err := getWinsize(os.Stdin.Fd()); err == nil {
+				setWinsize(ptmx.Fd(), ws)
+				rec.resize(ws.Cols, ws.Rows)
+			}
+		}
+	}()
+
+	copyDone := make(chan struct{})
+	go func() {
+		defer close(copyDone)
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := ptmx.Read(buf)
+			if n > 0 {
+				os.Stdout.Write(buf[:n])
+				rec.writeOutput(buf[:n])
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	waitErr := cmd.Wait()
+	<-copyDone
+	ptmx.Close()
+	return waitErr
+}
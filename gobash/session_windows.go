@@ -0,0 +1,10 @@
+//go:build windows
+package main
+import (
+	"fmt"
+	"os"
+)
+// runRecorded is not supported on platforms without a PTY.
+func runRecorded(path string, argv []string, env []string, logPath, logFormat string, stdin *os.File) error {
+	return fmt.Errorf("-log session recording is not supported on this platform")
+}